@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -10,11 +11,14 @@ import (
 	"time"
 
 	"github.com/Azure/brigade/pkg/brigade"
+	"github.com/Azure/brigade/pkg/storage"
 	"github.com/Azure/brigade/pkg/storage/kube"
 	"github.com/emicklei/go-restful"
 	restfulspec "github.com/emicklei/go-restful-openapi"
 
 	"github.com/Azure/brigade/pkg/api"
+	"github.com/Azure/brigade/pkg/api/auth"
+	"github.com/Azure/brigade/pkg/api/metrics"
 	"github.com/go-openapi/spec"
 	"k8s.io/api/core/v1"
 )
@@ -24,6 +28,13 @@ var (
 	master     string
 	namespace  string
 	apiPort    string
+
+	authMode         string
+	authOIDCIssuer   string
+	authOIDCClientID string
+	authStaticSecret string
+
+	readyzTimeout time.Duration
 )
 
 func init() {
@@ -31,6 +42,11 @@ func init() {
 	flag.StringVar(&master, "master", "", "master url")
 	flag.StringVar(&namespace, "namespace", defaultNamespace(), "kubernetes namespace")
 	flag.StringVar(&apiPort, "api-port", defaultAPIPort(), "TCP port to use for brigade-api")
+	flag.StringVar(&authMode, "auth-mode", defaultEnv("BRIGADE_AUTH_MODE", "none"), "request authentication mode: none, jwt, or oidc")
+	flag.StringVar(&authStaticSecret, "auth-static-secret", os.Getenv("BRIGADE_AUTH_STATIC_SECRET"), "HMAC secret used to verify HS256 tokens in jwt mode")
+	flag.StringVar(&authOIDCIssuer, "auth-oidc-issuer", os.Getenv("BRIGADE_AUTH_OIDC_ISSUER"), "OIDC discovery URL in oidc mode")
+	flag.StringVar(&authOIDCClientID, "auth-oidc-client-id", os.Getenv("BRIGADE_AUTH_OIDC_CLIENT_ID"), "OIDC client ID in oidc mode")
+	flag.DurationVar(&readyzTimeout, "readyz-timeout", 2*time.Second, "how long /readyz waits on the kube API before reporting not-ready")
 }
 
 type jobService struct {
@@ -46,6 +62,8 @@ type projectService struct {
 }
 
 type healthService struct {
+	store         storage.Store
+	readyzTimeout time.Duration
 }
 
 func (js jobService) WebService() *restful.WebService {
@@ -76,6 +94,19 @@ func (js jobService) WebService() *restful.WebService {
 		Returns(200, "OK", []byte{}).
 		Returns(404, "Not Found", nil))
 
+	ws.Route(ws.GET("/{id}/logs/stream").To(j.LogsStream).
+		Doc("stream job logs, optionally following new output as it is written").
+		Param(ws.PathParameter("id", "identifier of the job").DataType("string")).
+		Param(ws.QueryParameter("follow", "keep the stream open and deliver new log lines as they are written").DataType("boolean")).
+		Param(ws.QueryParameter("since", "only return log lines written at or after this RFC3339 timestamp or duration (e.g. \"10m\")").DataType("string")).
+		Param(ws.QueryParameter("tail", "only return the last N lines already written").DataType("integer")).
+		Param(ws.QueryParameter("timestamps", "prefix each line with its RFC3339Nano timestamp").DataType("boolean")).
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Produces(restful.MIME_JSON, "text/event-stream", "application/octet-stream").
+		Writes([]byte{}).
+		Returns(200, "OK", []byte{}).
+		Returns(404, "Not Found", nil))
+
 	return ws
 }
 
@@ -100,7 +131,7 @@ func (bs buildService) WebService() *restful.WebService {
 		Returns(200, "OK", brigade.Build{}).
 		Returns(404, "Not Found", nil))
 
-	ws.Route(ws.GET("/{id}/jobs").To(b.Jobs).
+	ws.Route(addListParams(ws.GET("/{id}/jobs").To(b.Jobs), ws, `"startTime" or "status"`).
 		Doc("get jobs of a build").
 		Param(ws.PathParameter("id", "id of the build").DataType("string")).
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -116,12 +147,44 @@ func (bs buildService) WebService() *restful.WebService {
 		Returns(200, "OK", []byte{}).
 		Returns(404, "Not Found", nil))
 
+	ws.Route(ws.GET("/{id}/logs/stream").To(b.LogsStream).
+		Doc("stream logs of a build's worker, optionally following new output as it is written").
+		Param(ws.PathParameter("id", "id of the build").DataType("string")).
+		Param(ws.QueryParameter("follow", "keep the stream open and deliver new log lines as they are written").DataType("boolean")).
+		Param(ws.QueryParameter("since", "only return log lines written at or after this RFC3339 timestamp or duration (e.g. \"10m\")").DataType("string")).
+		Param(ws.QueryParameter("tail", "only return the last N lines already written").DataType("integer")).
+		Param(ws.QueryParameter("timestamps", "prefix each line with its RFC3339Nano timestamp").DataType("boolean")).
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Produces(restful.MIME_JSON, "text/event-stream", "application/octet-stream").
+		Writes([]byte{}).
+		Returns(200, "OK", []byte{}).
+		Returns(404, "Not Found", nil))
+
+	ws.Route(ws.POST("/{id}/rerun").To(b.Rerun).
+		Doc("clone a finished build and resubmit it").
+		Param(ws.PathParameter("id", "id of the build to rerun").DataType("string")).
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Metadata(auth.KeyAuthRoles, []string{"operator"}).
+		Writes(brigade.Build{}).
+		Returns(202, "Accepted", brigade.Build{}).
+		Returns(404, "Not Found", nil))
+
+	ws.Route(ws.DELETE("/{id}").To(b.Cancel).
+		Doc("cancel a running build").
+		Param(ws.PathParameter("id", "id of the build to cancel").DataType("string")).
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Metadata(auth.KeyAuthRoles, []string{"operator"}).
+		Returns(202, "Accepted", nil).
+		Returns(404, "Not Found", nil).
+		Returns(409, "Conflict", nil))
+
 	return ws
 }
 
 func (ps projectService) WebService() *restful.WebService {
 	ws := new(restful.WebService)
 	p := ps.server.Project()
+	b := ps.server.Build()
 	// rest.GET("/projects", p.List)
 	// rest.GET("/project/:id", p.Get)
 	// rest.GET("/project/:id/builds", p.Builds)
@@ -133,7 +196,7 @@ func (ps projectService) WebService() *restful.WebService {
 
 	tags := []string{"projects"}
 
-	ws.Route(ws.GET("/projects").To(p.List).
+	ws.Route(addListParams(ws.GET("/projects").To(p.List), ws, `"name" or "id"`).
 		Doc("get all projects").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Writes([]brigade.Project{}).
@@ -148,7 +211,7 @@ func (ps projectService) WebService() *restful.WebService {
 		Returns(200, "OK", brigade.Project{}).
 		Returns(404, "Not Found", nil))
 
-	ws.Route(ws.GET("/project/{id}/builds").To(p.Builds).
+	ws.Route(addListParams(ws.GET("/project/{id}/builds").To(p.Builds), ws, `"startTime" or "status"`).
 		Doc("get list of builds for a project").
 		Param(ws.PathParameter("id", "id of the project").DataType("string")).
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -156,13 +219,50 @@ func (ps projectService) WebService() *restful.WebService {
 		Returns(200, "OK", []brigade.Build{}).
 		Returns(404, "Not Found", nil))
 
-	ws.Route(ws.GET("/projects-build").To(p.ListWithLatestBuild).
+	ws.Route(addListParams(ws.GET("/projects-build").To(p.ListWithLatestBuild), ws, `"name" or "id"`).
 		Doc("lists the projects with the latest builds attached.").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Writes([]api.ProjectBuildSummary{}).
 		Returns(200, "OK", []api.ProjectBuildSummary{}).
 		Returns(404, "Not Found", nil))
 
+	ws.Route(ws.POST("/project/{id}/build").To(b.Create).
+		Doc("create and submit a new build for a project").
+		Param(ws.PathParameter("id", "id of the project").DataType("string")).
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Metadata(auth.KeyAuthRoles, []string{"operator"}).
+		Reads(api.BuildCreateRequest{}).
+		Writes(brigade.Build{}).
+		Returns(201, "Created", brigade.Build{}).
+		Returns(400, "Bad Request", nil))
+
+	return ws
+}
+
+type eventsService struct {
+	server api.API
+}
+
+func (es eventsService) WebService() *restful.WebService {
+	ws := new(restful.WebService)
+	e := es.server.Events()
+	ws.
+		Path("/v1").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON, "text/event-stream")
+
+	tags := []string{"events"}
+
+	ws.Route(ws.GET("/events").To(e.Stream).
+		Doc("stream build and job lifecycle events (created, started, succeeded, failed, cancelled)").
+		Param(ws.QueryParameter("filters", "comma-separated label=value pairs and/or event type names to match").DataType("string")).
+		Param(ws.QueryParameter("since", "replay buffered events at or after this RFC3339 timestamp before streaming new ones").DataType("string")).
+		Param(ws.QueryParameter("until", "omit buffered events after this RFC3339 timestamp").DataType("string")).
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Produces("text/event-stream").
+		Writes(storage.Event{}).
+		Returns(200, "OK", storage.Event{}))
+
 	return ws
 }
 
@@ -186,6 +286,26 @@ func (hs healthService) WebService() *restful.WebService {
 	return ws
 }
 
+func (hs healthService) ReadyzWebService() *restful.WebService {
+	ws := new(restful.WebService)
+
+	ws.
+		Path("/readyz").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	tags := []string{"healthz"}
+
+	ws.Route(ws.GET("/").To(api.Readyz(hs.store, hs.readyzTimeout)).
+		Doc("get readiness status, backed by a bounded round-trip to the kube API").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Writes([]byte{}).
+		Returns(200, "OK", []byte{}).
+		Returns(503, "Service Unavailable", []byte{}))
+
+	return ws
+}
+
 func main() {
 	flag.Parse()
 	clientset, err := kube.GetClient(master, kubeconfig)
@@ -195,18 +315,34 @@ func main() {
 	}
 
 	storage := kube.New(clientset, namespace)
-	server := api.New(storage)
+	server := api.New(context.Background(), storage)
+
+	authenticator, err := auth.New(authMode, auth.Options{
+		StaticSecret: authStaticSecret,
+		OIDCIssuer:   authOIDCIssuer,
+		OIDCClientID: authOIDCClientID,
+	})
+	if err != nil {
+		log.Fatalf("error configuring authenticator (%s)", err)
+		return
+	}
 
 	j := jobService{server}
 	b := buildService{server}
 	p := projectService{server}
-	h := healthService{}
+	ev := eventsService{server}
+	h := healthService{store: storage, readyzTimeout: readyzTimeout}
 
 	restful.DefaultContainer.Add(j.WebService())
 	restful.DefaultContainer.Add(b.WebService())
 	restful.DefaultContainer.Add(p.WebService())
+	restful.DefaultContainer.Add(ev.WebService())
 	restful.DefaultContainer.Add(h.WebService())
+	restful.DefaultContainer.Add(h.ReadyzWebService())
+	restful.DefaultContainer.Filter(auth.Filter(authenticator))
+	restful.DefaultContainer.Filter(metrics.Filter())
 	restful.DefaultContainer.Filter(nCSACommonLogFormatLogger())
+	restful.DefaultContainer.Handle("/metrics", metrics.Handler())
 
 	config := restfulspec.Config{
 		WebServices: restful.RegisteredWebServices(), // you control what services are visible
@@ -248,6 +384,31 @@ func defaultAPIPort() string {
 	return "7745"
 }
 
+func defaultEnv(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return fallback
+}
+
+// addListParams documents the query parameters shared by every paginated
+// list endpoint: limit/continue for paging, labelSelector/fieldSelector
+// passed straight through to the underlying kube list call, and sortBy/order
+// for server-side sorting (sortFields describes the fields sortBy accepts
+// on this particular endpoint). Callers asking for the new
+// `{items, continue, remainingItemCount}` envelope opt in by setting any of
+// these, or by sending `Accept: application/vnd.brigade.list+json`; absent
+// those, the endpoint keeps returning its legacy bare array.
+func addListParams(rb *restful.RouteBuilder, ws *restful.WebService, sortFields string) *restful.RouteBuilder {
+	return rb.
+		Param(ws.QueryParameter("limit", "maximum number of items to return").DataType("integer")).
+		Param(ws.QueryParameter("continue", "opaque token from a previous response's continue field").DataType("string")).
+		Param(ws.QueryParameter("labelSelector", "Kubernetes label selector to filter by").DataType("string")).
+		Param(ws.QueryParameter("fieldSelector", "Kubernetes field selector to filter by").DataType("string")).
+		Param(ws.QueryParameter("sortBy", "sort by "+sortFields).DataType("string")).
+		Param(ws.QueryParameter("order", "sort order: \"asc\" or \"desc\"").DataType("string"))
+}
+
 func enrichSwaggerObject(swo *spec.Swagger) {
 	swo.Info = &spec.Info{
 		InfoProps: spec.InfoProps{
@@ -0,0 +1,50 @@
+// Package api implements the handlers behind brigade-api's go-restful
+// WebServices. It sits between the HTTP layer (brigade-api/cmd/brigade-api)
+// and a storage.Store backend, translating REST calls into storage
+// operations and Brigade domain objects into wire responses.
+package api
+
+import (
+	"context"
+
+	"github.com/Azure/brigade/pkg/storage"
+)
+
+// API is the root of the handler tree registered by brigade-api's
+// WebServices. Each method returns a resource-scoped handler set.
+type API interface {
+	Job() JobResource
+	Build() BuildResource
+	Project() ProjectResource
+	Events() EventsResource
+}
+
+type api struct {
+	store  storage.Store
+	broker *eventBroker
+}
+
+// New returns an API backed by store. If store implements
+// storage.EventSource, New starts the background watch that feeds
+// Events().Stream(); the watch stops when ctx is cancelled.
+func New(ctx context.Context, store storage.Store) API {
+	a := &api{store: store, broker: newEventBroker()}
+	if source, ok := store.(storage.EventSource); ok {
+		go a.broker.run(ctx, source)
+	}
+	return a
+}
+
+func (a *api) Job() JobResource         { return &jobResource{store: a.store} }
+func (a *api) Build() BuildResource     { return &buildResource{store: a.store} }
+func (a *api) Project() ProjectResource { return &projectResource{store: a.store} }
+func (a *api) Events() EventsResource   { return &eventsResource{broker: a.broker} }
+
+// ProjectBuildSummary pairs a Project with its most recent Build, used by
+// the `/v1/projects-build` listing.
+type ProjectBuildSummary struct {
+	ProjectID   string `json:"projectID"`
+	ProjectName string `json:"projectName"`
+	BuildID     string `json:"buildID,omitempty"`
+	Status      string `json:"status,omitempty"`
+}
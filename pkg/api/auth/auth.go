@@ -0,0 +1,52 @@
+// Package auth provides pluggable request authentication for brigade-api.
+// An Authenticator validates the credentials on an inbound request and
+// resolves them to a User; the go-restful filter in filter.go enforces
+// per-route role requirements on top of that.
+package auth
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrUnauthenticated is returned by Authenticate when the request carries
+// no usable credentials, or credentials that fail verification.
+var ErrUnauthenticated = errors.New("auth: request is not authenticated")
+
+// User is the principal resolved from a request's credentials.
+type User struct {
+	Name   string
+	Groups []string
+}
+
+// Authenticator validates the credentials on r and resolves them to a User.
+// Implementations should return ErrUnauthenticated (or a wrapping error) for
+// missing or invalid credentials rather than panicking or writing to w.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*User, error)
+}
+
+// Options configures the Authenticator implementations built by New. Only
+// the fields relevant to the selected mode need to be set.
+type Options struct {
+	// StaticSecret is an HMAC secret used to verify HS256 tokens in jwt mode.
+	StaticSecret string
+	// OIDCIssuer is the discovery URL of an OpenID Connect provider.
+	OIDCIssuer string
+	// OIDCClientID is the audience expected on OIDC ID tokens.
+	OIDCClientID string
+}
+
+// New builds the Authenticator for the named mode: "none", "jwt", or "oidc".
+func New(mode string, opts Options) (Authenticator, error) {
+	switch mode {
+	case "", "none":
+		return noneAuthenticator{}, nil
+	case "jwt":
+		return newJWTAuthenticator(opts)
+	case "oidc":
+		return newOIDCAuthenticator(opts)
+	default:
+		return nil, errors.New("auth: unknown auth mode " + mode)
+	}
+}
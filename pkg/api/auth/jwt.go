@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// jwtAuthenticator verifies bearer tokens signed with HS256 against a
+// static secret. RS256/JWKS verification is not implemented; configure a
+// JWKS-aware identity provider via --auth-mode=oidc instead.
+type jwtAuthenticator struct {
+	staticSecret []byte
+}
+
+func newJWTAuthenticator(opts Options) (Authenticator, error) {
+	if opts.StaticSecret == "" {
+		return nil, errors.New("auth: jwt mode requires --auth-static-secret")
+	}
+	return &jwtAuthenticator{staticSecret: []byte(opts.StaticSecret)}, nil
+}
+
+// Authenticate validates the `Authorization: Bearer <token>` header and
+// maps its claims onto a User.
+func (a *jwtAuthenticator) Authenticate(r *http.Request) (*User, error) {
+	raw := bearerToken(r)
+	if raw == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	token, err := jwt.Parse(raw, a.keyFunc)
+	if err != nil || !token.Valid {
+		return nil, ErrUnauthenticated
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	user := &User{}
+	if name, ok := claims["sub"].(string); ok {
+		user.Name = name
+	}
+	if groups, ok := claims["groups"].([]interface{}); ok {
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				user.Groups = append(user.Groups, s)
+			}
+		}
+	}
+	return user, nil
+}
+
+func (a *jwtAuthenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, errors.New("auth: unexpected signing method")
+	}
+	return a.staticSecret, nil
+}
+
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(h, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(h, prefix)
+}
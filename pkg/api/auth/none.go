@@ -0,0 +1,13 @@
+package auth
+
+import "net/http"
+
+// noneAuthenticator is the default Authenticator: every request is treated
+// as anonymous and authenticated, matching brigade-api's historical
+// behavior of exposing every endpoint without credentials.
+type noneAuthenticator struct{}
+
+// Authenticate always succeeds, resolving to the anonymous user.
+func (noneAuthenticator) Authenticate(r *http.Request) (*User, error) {
+	return &User{Name: "anonymous"}, nil
+}
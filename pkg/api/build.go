@@ -0,0 +1,206 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/Azure/brigade/pkg/brigade"
+	"github.com/Azure/brigade/pkg/storage"
+)
+
+// BuildResource handles requests scoped to a single Build, as well as
+// build logs.
+type BuildResource interface {
+	Get(req *restful.Request, resp *restful.Response)
+	Jobs(req *restful.Request, resp *restful.Response)
+	Logs(req *restful.Request, resp *restful.Response)
+	LogsStream(req *restful.Request, resp *restful.Response)
+	Create(req *restful.Request, resp *restful.Response)
+	Rerun(req *restful.Request, resp *restful.Response)
+	Cancel(req *restful.Request, resp *restful.Response)
+}
+
+// BuildCreateRequest is the body accepted by POST /v1/project/{id}/build.
+type BuildCreateRequest struct {
+	Event      string            `json:"event"`
+	Payload    []byte            `json:"payload,omitempty"`
+	Revision   *brigade.Revision `json:"revision,omitempty"`
+	Script     []byte            `json:"script,omitempty"`
+	ScriptName string            `json:"scriptName,omitempty"`
+}
+
+type buildResource struct {
+	store storage.Store
+}
+
+func (b *buildResource) Get(req *restful.Request, resp *restful.Response) {
+	id := req.PathParameter("id")
+	build, err := b.store.GetBuild(id)
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	resp.WriteEntity(build)
+}
+
+func (b *buildResource) Jobs(req *restful.Request, resp *restful.Response) {
+	id := req.PathParameter("id")
+	opts := listOptions(req)
+
+	lister, ok := b.store.(storage.ListingStore)
+	if ok && wantsListEnvelope(req, opts) {
+		list, err := lister.ListBuildJobs(id, opts)
+		if err != nil {
+			resp.WriteError(http.StatusNotFound, err)
+			return
+		}
+		resp.WriteEntity(list)
+		return
+	}
+
+	jobs, err := b.store.GetBuildJobs(id)
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	resp.WriteEntity(jobs)
+}
+
+func (b *buildResource) Logs(req *restful.Request, resp *restful.Response) {
+	id := req.PathParameter("id")
+	logs, err := b.store.GetBuildLogs(id)
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	resp.Write(logs)
+}
+
+// LogsStream streams a build's worker logs, honoring follow/since/tail/
+// timestamps the way Docker/Podman's container logs endpoint does.
+func (b *buildResource) LogsStream(req *restful.Request, resp *restful.Response) {
+	streamer, ok := b.store.(storage.LogStreamer)
+	if !ok {
+		resp.WriteError(http.StatusNotImplemented, fmt.Errorf("store does not support log streaming"))
+		return
+	}
+
+	id := req.PathParameter("id")
+	rc, err := streamer.GetBuildLogsStream(id, logStreamOptions(req))
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	streamLogs(req, resp, withCancel(req, rc))
+}
+
+// Create submits a new Build for the project named by the "id" path
+// parameter.
+func (b *buildResource) Create(req *restful.Request, resp *restful.Response) {
+	projectID := req.PathParameter("id")
+
+	in := new(BuildCreateRequest)
+	if err := req.ReadEntity(in); err != nil {
+		resp.WriteError(http.StatusBadRequest, err)
+		return
+	}
+	if in.Event == "" {
+		resp.WriteError(http.StatusBadRequest, fmt.Errorf("event is required"))
+		return
+	}
+
+	id, err := newBuildID()
+	if err != nil {
+		resp.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	build := &brigade.Build{
+		ID:         id,
+		ProjectID:  projectID,
+		Type:       in.Event,
+		Payload:    in.Payload,
+		Revision:   in.Revision,
+		Script:     in.Script,
+		ScriptName: in.ScriptName,
+		Status:     brigade.JobCreated,
+	}
+
+	created, err := b.store.CreateBuild(build)
+	if err != nil {
+		resp.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(http.StatusCreated, created)
+}
+
+// Rerun clones the build named by the "id" path parameter and resubmits it
+// as a new Build.
+func (b *buildResource) Rerun(req *restful.Request, resp *restful.Response) {
+	id := req.PathParameter("id")
+
+	original, err := b.store.GetBuild(id)
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+
+	newID, err := newBuildID()
+	if err != nil {
+		resp.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	clone := &brigade.Build{
+		ID:         newID,
+		ProjectID:  original.ProjectID,
+		Type:       original.Type,
+		Provider:   original.Provider,
+		Payload:    original.Payload,
+		Revision:   original.Revision,
+		Script:     original.Script,
+		ScriptName: original.ScriptName,
+		Status:     brigade.JobCreated,
+	}
+
+	rerun, err := b.store.CreateBuild(clone)
+	if err != nil {
+		resp.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(http.StatusAccepted, rerun)
+}
+
+// Cancel stops a running Build by deleting its worker pod.
+func (b *buildResource) Cancel(req *restful.Request, resp *restful.Response) {
+	id := req.PathParameter("id")
+
+	build, err := b.store.GetBuild(id)
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	if build.Status != brigade.JobRunning && build.Status != brigade.JobPending {
+		resp.WriteError(http.StatusConflict, fmt.Errorf("build %s is not running", id))
+		return
+	}
+
+	if err := b.store.CancelBuild(id); err != nil {
+		resp.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	resp.WriteHeader(http.StatusAccepted)
+}
+
+// newBuildID generates an opaque, collision-resistant Build identifier.
+func newBuildID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating build id: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/Azure/brigade/pkg/storage"
+)
+
+func mustJSON(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return []byte("{}")
+	}
+	return b
+}
+
+// EventsResource handles GET /v1/events, the SSE feed of build/job
+// lifecycle transitions.
+type EventsResource interface {
+	Stream(req *restful.Request, resp *restful.Response)
+}
+
+type eventsResource struct {
+	broker *eventBroker
+}
+
+// eventRingSize bounds how many past events Stream can replay via `since`.
+const eventRingSize = 1000
+
+// eventBroker fans a single storage.EventSource watch out to any number of
+// SSE subscribers, and keeps a bounded ring buffer so a client can replay
+// recent history via `since`/`until` instead of only seeing events that
+// arrive after it connects.
+type eventBroker struct {
+	subscribe   chan chan storage.Event
+	unsubscribe chan chan storage.Event
+
+	// ringMu guards ring/ringStart: remember runs in run's goroutine while
+	// replay is called from each Stream request's goroutine.
+	ringMu    sync.Mutex
+	ring      []storage.Event
+	ringStart int
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{
+		subscribe:   make(chan chan storage.Event),
+		unsubscribe: make(chan chan storage.Event),
+		ring:        make([]storage.Event, 0, eventRingSize),
+	}
+}
+
+// run consumes events from source and fans them out until ctx is cancelled.
+// It is meant to be started once, in a goroutine, for the lifetime of the
+// server.
+func (b *eventBroker) run(ctx context.Context, source storage.EventSource) {
+	events, err := source.WatchEvents(ctx)
+	if err != nil {
+		return
+	}
+
+	subscribers := map[chan storage.Event]bool{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ch := <-b.subscribe:
+			subscribers[ch] = true
+		case ch := <-b.unsubscribe:
+			delete(subscribers, ch)
+			close(ch)
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			b.remember(event)
+			for ch := range subscribers {
+				select {
+				case ch <- event:
+				default:
+					// Drop for a slow subscriber rather than block the feed
+					// for everyone else.
+				}
+			}
+		}
+	}
+}
+
+func (b *eventBroker) remember(event storage.Event) {
+	b.ringMu.Lock()
+	defer b.ringMu.Unlock()
+
+	if len(b.ring) < eventRingSize {
+		b.ring = append(b.ring, event)
+		return
+	}
+	b.ring[b.ringStart] = event
+	b.ringStart = (b.ringStart + 1) % eventRingSize
+}
+
+// replay returns buffered events matching since/until, oldest first.
+func (b *eventBroker) replay(since, until time.Time) []storage.Event {
+	b.ringMu.Lock()
+	defer b.ringMu.Unlock()
+
+	var out []storage.Event
+	n := len(b.ring)
+	for i := 0; i < n; i++ {
+		event := b.ring[(b.ringStart+i)%n]
+		if !since.IsZero() && event.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && event.Time.After(until) {
+			continue
+		}
+		out = append(out, event)
+	}
+	return out
+}
+
+func (b *eventBroker) addSubscriber() chan storage.Event {
+	ch := make(chan storage.Event, 64)
+	b.subscribe <- ch
+	return ch
+}
+
+func (b *eventBroker) removeSubscriber(ch chan storage.Event) {
+	b.unsubscribe <- ch
+}
+
+// eventFilters is the parsed form of the `filters` query parameter: a set
+// of label=value pairs and/or bare event-type names. An event matches when
+// it carries every label pair and, if any type names are given, its type is
+// one of them.
+type eventFilters struct {
+	labels map[string]string
+	types  map[string]bool
+}
+
+func parseEventFilters(raw string) eventFilters {
+	f := eventFilters{labels: map[string]string{}, types: map[string]bool{}}
+	if raw == "" {
+		return f
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		if k, v, ok := strings.Cut(pair, "="); ok {
+			f.labels[k] = v
+		} else if pair != "" {
+			f.types[pair] = true
+		}
+	}
+	return f
+}
+
+func (f eventFilters) matches(event storage.Event) bool {
+	if len(f.types) > 0 && !f.types[event.Type] {
+		return false
+	}
+	for k, v := range f.labels {
+		if event.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Stream serves GET /v1/events: an SSE feed of build/job lifecycle
+// transitions, optionally replaying buffered history via since/until and
+// narrowed by filters (a comma-separated list of label=value pairs and/or
+// event type names).
+func (e *eventsResource) Stream(req *restful.Request, resp *restful.Response) {
+	filters := parseEventFilters(req.QueryParameter("filters"))
+	since := parseEventTime(req.QueryParameter("since"))
+	until := parseEventTime(req.QueryParameter("until"))
+
+	resp.Header().Set("Content-Type", "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-cache")
+	resp.WriteHeader(http.StatusOK)
+	flusher, canFlush := resp.ResponseWriter.(http.Flusher)
+
+	writeEvent := func(event storage.Event) {
+		if !filters.matches(event) {
+			return
+		}
+		fmt.Fprintf(resp, "event: %s\ndata: %s\n\n", event.Kind, mustJSON(event))
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+
+	if !since.IsZero() || !until.IsZero() {
+		for _, event := range e.broker.replay(since, until) {
+			writeEvent(event)
+		}
+	}
+
+	ch := e.broker.addSubscriber()
+	defer e.broker.removeSubscriber(ch)
+
+	ctx := req.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			writeEvent(event)
+		}
+	}
+}
+
+func parseEventTime(raw string) time.Time {
+	if raw == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
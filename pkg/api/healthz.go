@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/Azure/brigade/pkg/storage"
+)
+
+// Healthz is a trivial liveness handler: if the process can answer HTTP at
+// all, it reports OK.
+func Healthz(req *restful.Request, resp *restful.Response) {
+	resp.Write([]byte("OK"))
+}
+
+// Readyz returns a handler reporting whether store can currently be
+// reached: a bounded round-trip, not just process liveness. Stores that
+// don't implement storage.Pinger are always considered ready.
+func Readyz(store storage.Store, timeout time.Duration) restful.RouteFunction {
+	return func(req *restful.Request, resp *restful.Response) {
+		pinger, ok := store.(storage.Pinger)
+		if !ok {
+			resp.Write([]byte("OK"))
+			return
+		}
+		if err := pinger.Ping(timeout); err != nil {
+			resp.WriteErrorString(http.StatusServiceUnavailable, err.Error()+"\n")
+			return
+		}
+		resp.Write([]byte("OK"))
+	}
+}
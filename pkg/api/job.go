@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/Azure/brigade/pkg/storage"
+)
+
+// JobResource handles requests scoped to a single Job.
+type JobResource interface {
+	Get(req *restful.Request, resp *restful.Response)
+	Logs(req *restful.Request, resp *restful.Response)
+	LogsStream(req *restful.Request, resp *restful.Response)
+}
+
+type jobResource struct {
+	store storage.Store
+}
+
+func (j *jobResource) Get(req *restful.Request, resp *restful.Response) {
+	id := req.PathParameter("id")
+	job, err := j.store.GetJob(id)
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	resp.WriteEntity(job)
+}
+
+func (j *jobResource) Logs(req *restful.Request, resp *restful.Response) {
+	id := req.PathParameter("id")
+	logs, err := j.store.GetJobLogs(id)
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	resp.Write(logs)
+}
+
+// LogsStream streams a job's logs, honoring follow/since/tail/timestamps
+// the way Docker/Podman's container logs endpoint does.
+func (j *jobResource) LogsStream(req *restful.Request, resp *restful.Response) {
+	streamer, ok := j.store.(storage.LogStreamer)
+	if !ok {
+		resp.WriteError(http.StatusNotImplemented, fmt.Errorf("store does not support log streaming"))
+		return
+	}
+
+	id := req.PathParameter("id")
+	rc, err := streamer.GetJobLogsStream(id, logStreamOptions(req))
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	streamLogs(req, resp, withCancel(req, rc))
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/Azure/brigade/pkg/storage"
+)
+
+// listEnvelopeMediaType is the media type clients opt into the paginated
+// `{items, continue, remainingItemCount}` envelope with; without it,
+// List/Builds/Jobs keep returning the legacy bare array so old clients
+// don't break.
+const listEnvelopeMediaType = "application/vnd.brigade.list+json"
+
+// listOptions builds a storage.ListOptions from the query parameters
+// shared by every paginated list endpoint: limit, continue, labelSelector,
+// fieldSelector, sortBy, and order.
+func listOptions(req *restful.Request) storage.ListOptions {
+	opts := storage.ListOptions{
+		Continue:      req.QueryParameter("continue"),
+		LabelSelector: req.QueryParameter("labelSelector"),
+		FieldSelector: req.QueryParameter("fieldSelector"),
+		SortBy:        req.QueryParameter("sortBy"),
+		Order:         req.QueryParameter("order"),
+	}
+	if limit := req.QueryParameter("limit"); limit != "" {
+		if n, err := strconv.ParseInt(limit, 10, 64); err == nil {
+			opts.Limit = n
+		}
+	}
+	return opts
+}
+
+// wantsListEnvelope reports whether the caller asked for the new
+// `{items, continue, remainingItemCount}` response shape, either by
+// negotiating the list media type via Accept or by using any of the new
+// query parameters.
+func wantsListEnvelope(req *restful.Request, opts storage.ListOptions) bool {
+	if req.Request.Header.Get("Accept") == listEnvelopeMediaType {
+		return true
+	}
+	return opts.Limit != 0 || opts.Continue != "" || opts.LabelSelector != "" ||
+		opts.FieldSelector != "" || opts.SortBy != "" || opts.Order != ""
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/emicklei/go-restful"
+	"github.com/gorilla/websocket"
+
+	"github.com/Azure/brigade/pkg/storage"
+)
+
+// logStreamOptions builds a storage.LogStreamOptions from the query
+// parameters Docker/Podman use on their `/containers/{id}/logs` endpoint:
+// follow, since, tail, and timestamps.
+func logStreamOptions(req *restful.Request) storage.LogStreamOptions {
+	opts := storage.LogStreamOptions{
+		Follow:     req.QueryParameter("follow") == "true",
+		Since:      req.QueryParameter("since"),
+		Timestamps: req.QueryParameter("timestamps") == "true",
+	}
+	if tail := req.QueryParameter("tail"); tail != "" {
+		if n, err := strconv.Atoi(tail); err == nil {
+			opts.Tail = n
+		}
+	}
+	return opts
+}
+
+// withCancel wraps rc so that the client disconnecting (req's context being
+// cancelled) closes the underlying stream, unblocking a Follow read that
+// would otherwise block until the pod exits on its own.
+func withCancel(req *restful.Request, rc io.ReadCloser) io.ReadCloser {
+	done := req.Request.Context().Done()
+	if done == nil {
+		return rc
+	}
+	go func() {
+		<-done
+		rc.Close()
+	}()
+	return rc
+}
+
+var upgrader = websocket.Upgrader{
+	// brigade-api is fronted by the same CORS policy as the rest of the
+	// REST surface, so the websocket upgrade doesn't need its own origin
+	// check here.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// streamLogs negotiates via Accept between chunked HTTP, SSE, and a
+// WebSocket upgrade, then copies rc to the client in the negotiated
+// framing until rc or the client connection closes.
+func streamLogs(req *restful.Request, resp *restful.Response, rc io.ReadCloser) {
+	defer rc.Close()
+
+	httpReq := req.Request
+	httpResp := resp.ResponseWriter
+
+	if websocket.IsWebSocketUpgrade(httpReq) {
+		streamLogsWebSocket(httpResp, httpReq, rc)
+		return
+	}
+
+	accept := httpReq.Header.Get("Accept")
+	if accept == "text/event-stream" {
+		streamLogsSSE(resp, rc)
+		return
+	}
+
+	streamLogsChunked(resp, rc)
+}
+
+func streamLogsChunked(resp *restful.Response, rc io.ReadCloser) {
+	resp.Header().Set("Content-Type", "application/octet-stream")
+	resp.WriteHeader(http.StatusOK)
+	flusher, canFlush := resp.ResponseWriter.(http.Flusher)
+
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		fmt.Fprintf(resp, "%s\n", scanner.Bytes())
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+}
+
+func streamLogsSSE(resp *restful.Response, rc io.ReadCloser) {
+	resp.Header().Set("Content-Type", "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-cache")
+	resp.WriteHeader(http.StatusOK)
+	flusher, canFlush := resp.ResponseWriter.(http.Flusher)
+
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		fmt.Fprintf(resp, "event: log\ndata: %s\n\n", scanner.Bytes())
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+}
+
+func streamLogsWebSocket(w http.ResponseWriter, r *http.Request, rc io.ReadCloser) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		if err := conn.WriteMessage(websocket.TextMessage, scanner.Bytes()); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Package metrics holds brigade's Prometheus collectors: a
+// request-instrumentation filter for go-restful servers such as brigade-api,
+// and a counter for errors surfaced from the kube storage backend.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "brigade_api_requests_total",
+		Help: "Total number of HTTP requests, by method, route, and status code.",
+	}, []string{"method", "route", "code"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "brigade_api_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, by method, route, and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "code"})
+
+	requestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "brigade_api_requests_in_flight",
+		Help: "Number of HTTP requests currently being served.",
+	})
+
+	// KubeErrors counts errors the storage layer surfaces from the
+	// Kubernetes API, labeled by the operation that failed (e.g. "get-pod",
+	// "create-secret"). pkg/storage/kube calls IncKubeError on failure.
+	KubeErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "brigade_api_kube_errors_total",
+		Help: "Total number of errors returned by the Kubernetes API to the storage layer, by operation.",
+	}, []string{"operation"})
+)
+
+func init() {
+	prometheus.MustRegister(requestsTotal, requestDuration, requestsInFlight, KubeErrors)
+}
+
+// IncKubeError records a Kubernetes API error observed by the storage layer
+// for the given operation (e.g. "get-pod", "create-secret").
+func IncKubeError(operation string) {
+	KubeErrors.WithLabelValues(operation).Inc()
+}
+
+// Handler serves the Prometheus text exposition format for /metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Filter returns a restful.FilterFunction that records per-route request
+// counts, status-code counters, and latency histograms labeled by method,
+// route, and code, plus a requests-in-flight gauge. Register it as a
+// sibling to the request logger.
+func Filter() restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		requestsInFlight.Inc()
+		defer requestsInFlight.Dec()
+
+		start := time.Now()
+		chain.ProcessFilter(req, resp)
+		elapsed := time.Since(start).Seconds()
+
+		route := "unmatched"
+		if r := req.SelectedRoute(); r != nil {
+			route = r.Path
+		}
+		code := strconv.Itoa(resp.StatusCode())
+
+		requestsTotal.WithLabelValues(req.Request.Method, route, code).Inc()
+		requestDuration.WithLabelValues(req.Request.Method, route, code).Observe(elapsed)
+	}
+}
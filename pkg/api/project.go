@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/Azure/brigade/pkg/brigade"
+	"github.com/Azure/brigade/pkg/storage"
+)
+
+// ProjectResource handles requests scoped to Projects: listing, fetching a
+// single Project, and listing a Project's Builds.
+type ProjectResource interface {
+	List(req *restful.Request, resp *restful.Response)
+	Get(req *restful.Request, resp *restful.Response)
+	Builds(req *restful.Request, resp *restful.Response)
+	ListWithLatestBuild(req *restful.Request, resp *restful.Response)
+}
+
+type projectResource struct {
+	store storage.Store
+}
+
+func (p *projectResource) List(req *restful.Request, resp *restful.Response) {
+	opts := listOptions(req)
+
+	lister, ok := p.store.(storage.ListingStore)
+	if ok && wantsListEnvelope(req, opts) {
+		list, err := lister.ListProjects(opts)
+		if err != nil {
+			resp.WriteError(http.StatusNotFound, err)
+			return
+		}
+		resp.WriteEntity(list)
+		return
+	}
+
+	projects, err := p.store.GetProjects()
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	resp.WriteEntity(projects)
+}
+
+func (p *projectResource) Get(req *restful.Request, resp *restful.Response) {
+	id := req.PathParameter("id")
+	project, err := p.store.GetProject(id)
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	resp.WriteEntity(project)
+}
+
+func (p *projectResource) Builds(req *restful.Request, resp *restful.Response) {
+	id := req.PathParameter("id")
+	opts := listOptions(req)
+
+	lister, ok := p.store.(storage.ListingStore)
+	if ok && wantsListEnvelope(req, opts) {
+		list, err := lister.ListProjectBuilds(id, opts)
+		if err != nil {
+			resp.WriteError(http.StatusNotFound, err)
+			return
+		}
+		resp.WriteEntity(list)
+		return
+	}
+
+	builds, err := p.store.GetProjectBuilds(id)
+	if err != nil {
+		resp.WriteError(http.StatusNotFound, err)
+		return
+	}
+	resp.WriteEntity(builds)
+}
+
+func (p *projectResource) ListWithLatestBuild(req *restful.Request, resp *restful.Response) {
+	opts := listOptions(req)
+
+	var projects []*brigade.Project
+	var list *storage.List
+	lister, ok := p.store.(storage.ListingStore)
+	envelope := ok && wantsListEnvelope(req, opts)
+	if envelope {
+		var err error
+		list, err = lister.ListProjects(opts)
+		if err != nil {
+			resp.WriteError(http.StatusNotFound, err)
+			return
+		}
+		projects = list.Items.([]*brigade.Project)
+	} else {
+		var err error
+		projects, err = p.store.GetProjects()
+		if err != nil {
+			resp.WriteError(http.StatusNotFound, err)
+			return
+		}
+	}
+
+	summaries := make([]ProjectBuildSummary, 0, len(projects))
+	for _, proj := range projects {
+		summary := ProjectBuildSummary{ProjectID: proj.ID, ProjectName: proj.Name}
+		builds, err := p.store.GetProjectBuilds(proj.ID)
+		if err == nil && len(builds) > 0 {
+			latest := builds[0]
+			for _, b := range builds[1:] {
+				if b.Started.After(latest.Started) {
+					latest = b
+				}
+			}
+			summary.BuildID = latest.ID
+			summary.Status = string(latest.Status)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if envelope {
+		list.Items = summaries
+		resp.WriteEntity(list)
+		return
+	}
+	resp.WriteEntity(summaries)
+}
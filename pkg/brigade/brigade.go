@@ -0,0 +1,68 @@
+// Package brigade describes the core domain types shared by the API server,
+// controller, and storage backends: Projects, Builds, and Jobs.
+package brigade
+
+import "time"
+
+// JobStatus describes the state of a Job or Build.
+type JobStatus string
+
+// The recognized lifecycle states for a Build or Job.
+const (
+	JobCreated   JobStatus = "created"
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+// Project represents a Brigade project: a repository plus the configuration
+// (secrets, default script, etc.) needed to run builds against it.
+type Project struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Repo         Repo              `json:"repo"`
+	DefaultScript string           `json:"defaultScript,omitempty"`
+	Secrets      map[string]string `json:"-"`
+}
+
+// Repo describes the VCS repository a Project is bound to.
+type Repo struct {
+	Name     string `json:"name"`
+	CloneURL string `json:"cloneURL"`
+}
+
+// Build represents one run of a Project's script in response to an event.
+type Build struct {
+	ID         string    `json:"id"`
+	ProjectID  string    `json:"projectID"`
+	Type       string    `json:"type"`
+	Provider   string    `json:"provider"`
+	Revision   *Revision `json:"revision,omitempty"`
+	Payload    []byte    `json:"payload,omitempty"`
+	Script     []byte    `json:"script,omitempty"`
+	ScriptName string    `json:"scriptName,omitempty"`
+	Status     JobStatus `json:"status"`
+	Started    time.Time `json:"started,omitempty"`
+	Ended      time.Time `json:"ended,omitempty"`
+}
+
+// Revision pins a Build to a specific commit/ref of a Project's repository.
+type Revision struct {
+	Commit string `json:"commit,omitempty"`
+	Ref    string `json:"ref,omitempty"`
+}
+
+// Job represents a single unit of work (one container) executed as part of
+// a Build's worker pod.
+type Job struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	BuildID string    `json:"buildID"`
+	Image   string    `json:"image"`
+	Status  JobStatus `json:"status"`
+	ExitCode int32    `json:"exitCode,omitempty"`
+	Started time.Time `json:"started,omitempty"`
+	Ended   time.Time `json:"ended,omitempty"`
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// Event describes a single build or job lifecycle transition.
+type Event struct {
+	Type      string    `json:"type"` // created, started, succeeded, failed, cancelled
+	Kind      string    `json:"kind"` // build or job
+	ID        string    `json:"id"`
+	ProjectID string    `json:"projectID,omitempty"`
+	Time      time.Time `json:"time"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// EventSource is implemented by stores that can emit build/job lifecycle
+// events as they happen, e.g. by watching Kubernetes pods.
+type EventSource interface {
+	// WatchEvents streams lifecycle events until ctx is cancelled, at which
+	// point the returned channel is closed.
+	WatchEvents(ctx context.Context) (<-chan Event, error)
+}
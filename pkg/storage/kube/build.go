@@ -0,0 +1,93 @@
+package kube
+
+import (
+	"fmt"
+
+	"github.com/Azure/brigade/pkg/api/metrics"
+	"github.com/Azure/brigade/pkg/brigade"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	labelComponent = "component"
+	labelBuild     = "build"
+	labelProject   = "project"
+	labelHeritage  = "heritage"
+)
+
+// GetBuild implements storage.Store by fetching the build's Secret.
+func (s *Storage) GetBuild(id string) (*brigade.Build, error) {
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(s.buildPodName(id), metav1.GetOptions{})
+	if err != nil {
+		metrics.IncKubeError("get-secret")
+		return nil, fmt.Errorf("error getting build %s: %v", id, err)
+	}
+	return buildFromSecret(secret), nil
+}
+
+// GetProjectBuilds implements storage.Store by listing the Secrets labeled
+// as builds for projectID.
+func (s *Storage) GetProjectBuilds(projectID string) ([]*brigade.Build, error) {
+	secrets, err := s.client.CoreV1().Secrets(s.namespace).List(metav1.ListOptions{
+		LabelSelector: labelComponent + "=" + labelBuild + "," + labelProject + "=" + projectID,
+	})
+	if err != nil {
+		metrics.IncKubeError("list-secrets")
+		return nil, fmt.Errorf("error listing builds for project %s: %v", projectID, err)
+	}
+
+	builds := make([]*brigade.Build, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		builds = append(builds, buildFromSecret(&secrets.Items[i]))
+	}
+	return builds, nil
+}
+
+// CreateBuild implements storage.Store by writing b as a Secret. The
+// brigade-controller watches these secrets and schedules the worker pod
+// that actually runs the build.
+func (s *Storage) CreateBuild(b *brigade.Build) (*brigade.Build, error) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.buildPodName(b.ID),
+			Namespace: s.namespace,
+			Labels: map[string]string{
+				labelHeritage:  "brigade",
+				labelComponent: labelBuild,
+				labelProject:   b.ProjectID,
+				"build":        b.ID,
+			},
+		},
+		Type: "brigade.sh/build",
+		Data: map[string][]byte{
+			"event_type":  []byte(b.Type),
+			"payload":     b.Payload,
+			"script":      b.Script,
+			"script_name": []byte(b.ScriptName),
+		},
+	}
+	if b.Revision != nil {
+		secret.Data["revision_commit"] = []byte(b.Revision.Commit)
+		secret.Data["revision_ref"] = []byte(b.Revision.Ref)
+	}
+
+	if _, err := s.client.CoreV1().Secrets(s.namespace).Create(secret); err != nil {
+		metrics.IncKubeError("create-secret")
+		return nil, fmt.Errorf("error creating build %s: %v", b.ID, err)
+	}
+	return b, nil
+}
+
+// CancelBuild implements storage.Store by deleting the build's worker pod.
+// The controller observes the deletion and marks the build cancelled; it
+// does not resubmit work that the pod had already started.
+func (s *Storage) CancelBuild(id string) error {
+	err := s.client.CoreV1().Pods(s.namespace).Delete(s.buildPodName(id), &metav1.DeleteOptions{})
+	if err != nil {
+		metrics.IncKubeError("delete-pod")
+		return fmt.Errorf("error cancelling build %s: %v", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package kube
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/brigade/pkg/storage"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WatchEvents implements storage.EventSource on top of a shared informer
+// watching pods labeled heritage=brigade in s.namespace, translating pod
+// phase transitions into build/job lifecycle Events.
+func (s *Storage) WatchEvents(ctx context.Context) (<-chan storage.Event, error) {
+	out := make(chan storage.Event, 256)
+
+	factory := informers.NewSharedInformerFactoryWithOptions(s.client, 30*time.Second,
+		informers.WithNamespace(s.namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = labelHeritage + "=brigade"
+		}),
+	)
+	informer := factory.Core().V1().Pods().Informer()
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			emitPodEvent(out, obj, "created")
+		},
+		UpdateFunc: func(oldObj, obj interface{}) {
+			oldPod, oldOK := oldObj.(*v1.Pod)
+			newPod, newOK := obj.(*v1.Pod)
+			if oldOK && newOK && oldPod.Status.Phase == newPod.Status.Phase {
+				return
+			}
+			emitPodEvent(out, obj, "")
+		},
+		DeleteFunc: func(obj interface{}) {
+			emitPodDeleteEvent(out, obj)
+		},
+	})
+
+	go informer.Run(ctx.Done())
+
+	go func() {
+		<-ctx.Done()
+		close(out)
+	}()
+
+	return out, nil
+}
+
+func emitPodEvent(out chan<- storage.Event, obj interface{}, forcedType string) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return
+	}
+
+	kind := "job"
+	if pod.Labels[labelComponent] == labelBuild {
+		kind = "build"
+	}
+
+	eventType := forcedType
+	if eventType == "" {
+		eventType = podPhaseEventType(pod.Status.Phase)
+	}
+	if eventType == "" {
+		return
+	}
+
+	event := storage.Event{
+		Type:      eventType,
+		Kind:      kind,
+		ID:        pod.Labels[kind],
+		ProjectID: pod.Labels[labelProject],
+		Time:      time.Now(),
+		Labels:    pod.Labels,
+	}
+
+	select {
+	case out <- event:
+	default:
+		// A slow or absent consumer shouldn't block the informer's resync
+		// loop; dropping here is preferable to unbounded buffering.
+	}
+}
+
+// emitPodDeleteEvent handles a pod's removal from the informer's cache.
+// A pod that already reached a terminal phase (succeeded/failed) was
+// already reported as such by UpdateFunc; its later deletion is just the
+// backend garbage-collecting a finished pod, not a cancellation, so it is
+// not re-emitted. Only pods deleted while still pending/running are
+// reported, as "cancelled".
+func emitPodDeleteEvent(out chan<- storage.Event, obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		pod, ok = tombstone.Obj.(*v1.Pod)
+		if !ok {
+			return
+		}
+	}
+
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return
+	}
+	emitPodEvent(out, pod, "cancelled")
+}
+
+func podPhaseEventType(phase v1.PodPhase) string {
+	switch phase {
+	case v1.PodRunning:
+		return "started"
+	case v1.PodSucceeded:
+		return "succeeded"
+	case v1.PodFailed:
+		return "failed"
+	default:
+		return ""
+	}
+}
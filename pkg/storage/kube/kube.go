@@ -0,0 +1,100 @@
+// Package kube implements pkg/storage.Store on top of a Kubernetes cluster:
+// Projects and Builds are stored as Secrets, and each Build/Job maps to a
+// pod whose name is derived from its ID.
+package kube
+
+import (
+	"fmt"
+
+	"github.com/Azure/brigade/pkg/api/metrics"
+	"github.com/Azure/brigade/pkg/brigade"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Storage implements storage.Store against a Kubernetes clientset.
+type Storage struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// New returns a Storage that reads and writes Brigade objects in the given
+// namespace using client.
+func New(client kubernetes.Interface, namespace string) *Storage {
+	return &Storage{client: client, namespace: namespace}
+}
+
+// GetClient builds a Kubernetes clientset. When kubeconfig is empty it uses
+// the in-cluster config if one is available; otherwise, or when kubeconfig
+// is set, it builds the config from the given kubeconfig/master pair.
+func GetClient(master, kubeconfig string) (kubernetes.Interface, error) {
+	config, err := getClientConfig(master, kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+func getClientConfig(master, kubeconfig string) (*rest.Config, error) {
+	if kubeconfig == "" {
+		config, err := rest.InClusterConfig()
+		if err == nil {
+			return config, nil
+		}
+	}
+	config, err := clientcmd.BuildConfigFromFlags(master, kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error building kube config: %v", err)
+	}
+	return config, nil
+}
+
+func (s *Storage) buildPodName(buildID string) string {
+	return fmt.Sprintf("brigade-worker-%s", buildID)
+}
+
+func (s *Storage) jobPodName(jobID string) string {
+	return fmt.Sprintf("brigade-job-%s", jobID)
+}
+
+// GetProjects implements storage.Store.
+func (s *Storage) GetProjects() ([]*brigade.Project, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// GetProject implements storage.Store.
+func (s *Storage) GetProject(id string) (*brigade.Project, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// GetBuildJobs implements storage.Store.
+func (s *Storage) GetBuildJobs(buildID string) ([]*brigade.Job, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// GetJob implements storage.Store.
+func (s *Storage) GetJob(id string) (*brigade.Job, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// GetBuildLogs implements storage.Store.
+func (s *Storage) GetBuildLogs(buildID string) ([]byte, error) {
+	return s.podLogs(s.buildPodName(buildID))
+}
+
+// GetJobLogs implements storage.Store.
+func (s *Storage) GetJobLogs(jobID string) ([]byte, error) {
+	return s.podLogs(s.jobPodName(jobID))
+}
+
+func (s *Storage) podLogs(podName string) ([]byte, error) {
+	req := s.client.CoreV1().Pods(s.namespace).GetLogs(podName, &v1.PodLogOptions{})
+	raw, err := req.DoRaw()
+	if err != nil {
+		metrics.IncKubeError("get-pod-logs")
+	}
+	return raw, err
+}
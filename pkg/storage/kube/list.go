@@ -0,0 +1,348 @@
+package kube
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/Azure/brigade/pkg/api/metrics"
+	"github.com/Azure/brigade/pkg/brigade"
+	"github.com/Azure/brigade/pkg/storage"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const labelProjectFlag = "project-id"
+
+func toKubeListOptions(opts storage.ListOptions, extraSelector string) metav1.ListOptions {
+	selector := opts.LabelSelector
+	if extraSelector != "" {
+		if selector != "" {
+			selector = selector + "," + extraSelector
+		} else {
+			selector = extraSelector
+		}
+	}
+	return metav1.ListOptions{
+		Limit:         opts.Limit,
+		Continue:      opts.Continue,
+		LabelSelector: selector,
+		FieldSelector: opts.FieldSelector,
+	}
+}
+
+// ListProjects implements storage.ListingStore against Secrets labeled as
+// brigade projects, optionally sorted by name or id. See ListProjectBuilds
+// for why a sort forces fetching the full result set instead of paginating
+// with kube's continue token.
+func (s *Storage) ListProjects(opts storage.ListOptions) (*storage.List, error) {
+	extraSelector := labelComponent + "=project"
+
+	if opts.SortBy == "" {
+		secrets, err := s.client.CoreV1().Secrets(s.namespace).List(toKubeListOptions(opts, extraSelector))
+		if err != nil {
+			metrics.IncKubeError("list-secrets")
+			return nil, err
+		}
+
+		projects := make([]*brigade.Project, 0, len(secrets.Items))
+		for i := range secrets.Items {
+			projects = append(projects, projectFromSecret(&secrets.Items[i]))
+		}
+		return &storage.List{
+			Items:              projects,
+			Continue:           secrets.Continue,
+			RemainingItemCount: secrets.RemainingItemCount,
+		}, nil
+	}
+
+	secrets, err := s.listAllSecrets(mergeSelector(opts.LabelSelector, extraSelector), opts.FieldSelector)
+	if err != nil {
+		metrics.IncKubeError("list-secrets")
+		return nil, err
+	}
+
+	projects := make([]*brigade.Project, 0, len(secrets))
+	for i := range secrets {
+		projects = append(projects, projectFromSecret(&secrets[i]))
+	}
+	sortProjects(projects, opts.SortBy, opts.Order)
+
+	start, end, cont, remaining := paginate(len(projects), opts)
+	return &storage.List{
+		Items:              projects[start:end],
+		Continue:           cont,
+		RemainingItemCount: remaining,
+	}, nil
+}
+
+// ListProjectBuilds implements storage.ListingStore against Secrets labeled
+// as brigade builds for a project, optionally sorted by startTime or
+// status. Sorting requires the full result set: kube's continue token only
+// pages through its own (creation) order, so applying it before sorting
+// would return a page that's correctly paginated but incorrectly sorted.
+// When a sort is requested, every matching Secret is fetched, sorted, and
+// paginated in memory instead.
+func (s *Storage) ListProjectBuilds(projectID string, opts storage.ListOptions) (*storage.List, error) {
+	extraSelector := labelComponent + "=" + labelBuild + "," + labelProject + "=" + projectID
+
+	if opts.SortBy == "" {
+		secrets, err := s.client.CoreV1().Secrets(s.namespace).List(toKubeListOptions(opts, extraSelector))
+		if err != nil {
+			metrics.IncKubeError("list-secrets")
+			return nil, err
+		}
+
+		builds := make([]*brigade.Build, 0, len(secrets.Items))
+		for i := range secrets.Items {
+			builds = append(builds, buildFromSecret(&secrets.Items[i]))
+		}
+		return &storage.List{
+			Items:              builds,
+			Continue:           secrets.Continue,
+			RemainingItemCount: secrets.RemainingItemCount,
+		}, nil
+	}
+
+	secrets, err := s.listAllSecrets(mergeSelector(opts.LabelSelector, extraSelector), opts.FieldSelector)
+	if err != nil {
+		metrics.IncKubeError("list-secrets")
+		return nil, err
+	}
+
+	builds := make([]*brigade.Build, 0, len(secrets))
+	for i := range secrets {
+		builds = append(builds, buildFromSecret(&secrets[i]))
+	}
+	sortBuilds(builds, opts.SortBy, opts.Order)
+
+	start, end, cont, remaining := paginate(len(builds), opts)
+	return &storage.List{
+		Items:              builds[start:end],
+		Continue:           cont,
+		RemainingItemCount: remaining,
+	}, nil
+}
+
+// ListBuildJobs implements storage.ListingStore against pods labeled as
+// jobs for a build. See ListProjectBuilds for why a sort forces fetching
+// the full result set instead of paginating with kube's continue token.
+func (s *Storage) ListBuildJobs(buildID string, opts storage.ListOptions) (*storage.List, error) {
+	extraSelector := labelComponent + "=job," + labelBuild + "=" + buildID
+
+	if opts.SortBy == "" {
+		pods, err := s.client.CoreV1().Pods(s.namespace).List(toKubeListOptions(opts, extraSelector))
+		if err != nil {
+			metrics.IncKubeError("list-pods")
+			return nil, err
+		}
+
+		jobs := make([]*brigade.Job, 0, len(pods.Items))
+		for i := range pods.Items {
+			jobs = append(jobs, jobFromPod(&pods.Items[i]))
+		}
+		return &storage.List{
+			Items:              jobs,
+			Continue:           pods.Continue,
+			RemainingItemCount: pods.RemainingItemCount,
+		}, nil
+	}
+
+	pods, err := s.listAllPods(mergeSelector(opts.LabelSelector, extraSelector), opts.FieldSelector)
+	if err != nil {
+		metrics.IncKubeError("list-pods")
+		return nil, err
+	}
+
+	jobs := make([]*brigade.Job, 0, len(pods))
+	for i := range pods {
+		jobs = append(jobs, jobFromPod(&pods[i]))
+	}
+	sortJobs(jobs, opts.SortBy, opts.Order)
+
+	start, end, cont, remaining := paginate(len(jobs), opts)
+	return &storage.List{
+		Items:              jobs[start:end],
+		Continue:           cont,
+		RemainingItemCount: remaining,
+	}, nil
+}
+
+func mergeSelector(labelSelector, extraSelector string) string {
+	if labelSelector == "" {
+		return extraSelector
+	}
+	return labelSelector + "," + extraSelector
+}
+
+// listAllSecrets fetches every Secret matching the selectors, following
+// kube's continue token until exhausted.
+func (s *Storage) listAllSecrets(labelSelector, fieldSelector string) ([]v1.Secret, error) {
+	var all []v1.Secret
+	continueToken := ""
+	for {
+		list, err := s.client.CoreV1().Secrets(s.namespace).List(metav1.ListOptions{
+			LabelSelector: labelSelector,
+			FieldSelector: fieldSelector,
+			Continue:      continueToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, list.Items...)
+		if list.Continue == "" {
+			return all, nil
+		}
+		continueToken = list.Continue
+	}
+}
+
+// listAllPods fetches every Pod matching the selectors, following kube's
+// continue token until exhausted.
+func (s *Storage) listAllPods(labelSelector, fieldSelector string) ([]v1.Pod, error) {
+	var all []v1.Pod
+	continueToken := ""
+	for {
+		list, err := s.client.CoreV1().Pods(s.namespace).List(metav1.ListOptions{
+			LabelSelector: labelSelector,
+			FieldSelector: fieldSelector,
+			Continue:      continueToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, list.Items...)
+		if list.Continue == "" {
+			return all, nil
+		}
+		continueToken = list.Continue
+	}
+}
+
+// paginate slices a fully sorted, in-memory result set into the page
+// requested by opts, synthesizing its own offset-based Continue token
+// since kube's is only meaningful for its own unsorted ordering.
+func paginate(total int, opts storage.ListOptions) (start, end int, cont string, remaining *int64) {
+	start = 0
+	if n, err := strconv.ParseInt(opts.Continue, 10, 64); err == nil && n > 0 && n <= int64(total) {
+		start = int(n)
+	}
+
+	end = total
+	if opts.Limit > 0 && int64(start)+opts.Limit < int64(total) {
+		end = start + int(opts.Limit)
+	}
+
+	if end < total {
+		cont = strconv.Itoa(end)
+		r := int64(total - end)
+		remaining = &r
+	}
+	return start, end, cont, remaining
+}
+
+func projectFromSecret(secret *v1.Secret) *brigade.Project {
+	return &brigade.Project{
+		ID:   secret.Labels[labelProjectFlag],
+		Name: secret.Name,
+	}
+}
+
+func buildFromSecret(secret *v1.Secret) *brigade.Build {
+	build := &brigade.Build{
+		ID:         secret.Labels["build"],
+		ProjectID:  secret.Labels[labelProject],
+		Type:       string(secret.Data["event_type"]),
+		Payload:    secret.Data["payload"],
+		Script:     secret.Data["script"],
+		ScriptName: string(secret.Data["script_name"]),
+		Status:     brigade.JobStatus(secret.Labels["status"]),
+		Started:    secret.CreationTimestamp.Time,
+	}
+	if commit, ref := secret.Data["revision_commit"], secret.Data["revision_ref"]; len(commit) > 0 || len(ref) > 0 {
+		build.Revision = &brigade.Revision{Commit: string(commit), Ref: string(ref)}
+	}
+	return build
+}
+
+func jobFromPod(pod *v1.Pod) *brigade.Job {
+	return &brigade.Job{
+		ID:      pod.Labels["job"],
+		Name:    pod.Name,
+		BuildID: pod.Labels[labelBuild],
+		Status:  brigade.JobStatus(podPhaseStatus(pod.Status.Phase)),
+		Started: pod.CreationTimestamp.Time,
+	}
+}
+
+func podPhaseStatus(phase v1.PodPhase) string {
+	switch phase {
+	case v1.PodPending:
+		return string(brigade.JobPending)
+	case v1.PodRunning:
+		return string(brigade.JobRunning)
+	case v1.PodSucceeded:
+		return string(brigade.JobSucceeded)
+	case v1.PodFailed:
+		return string(brigade.JobFailed)
+	default:
+		return string(phase)
+	}
+}
+
+func sortProjects(projects []*brigade.Project, sortBy, order string) {
+	if sortBy == "" {
+		return
+	}
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "id":
+			return projects[i].ID < projects[j].ID
+		default: // "name"
+			return projects[i].Name < projects[j].Name
+		}
+	}
+	if order == "desc" {
+		sort.SliceStable(projects, func(i, j int) bool { return less(j, i) })
+	} else {
+		sort.SliceStable(projects, less)
+	}
+}
+
+func sortBuilds(builds []*brigade.Build, sortBy, order string) {
+	if sortBy == "" {
+		return
+	}
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "status":
+			return builds[i].Status < builds[j].Status
+		default: // "startTime"
+			return builds[i].Started.Before(builds[j].Started)
+		}
+	}
+	if order == "desc" {
+		sort.SliceStable(builds, func(i, j int) bool { return less(j, i) })
+	} else {
+		sort.SliceStable(builds, less)
+	}
+}
+
+func sortJobs(jobs []*brigade.Job, sortBy, order string) {
+	if sortBy == "" {
+		return
+	}
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "status":
+			return jobs[i].Status < jobs[j].Status
+		default: // "startTime"
+			return jobs[i].Started.Before(jobs[j].Started)
+		}
+	}
+	if order == "desc" {
+		sort.SliceStable(jobs, func(i, j int) bool { return less(j, i) })
+	} else {
+		sort.SliceStable(jobs, less)
+	}
+}
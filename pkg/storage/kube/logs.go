@@ -0,0 +1,46 @@
+package kube
+
+import (
+	"io"
+	"time"
+
+	"github.com/Azure/brigade/pkg/storage"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetBuildLogsStream implements storage.LogStreamer, streaming the build
+// worker pod's logs from the Kubernetes pod log API.
+func (s *Storage) GetBuildLogsStream(buildID string, opts storage.LogStreamOptions) (io.ReadCloser, error) {
+	return s.podLogsStream(s.buildPodName(buildID), opts)
+}
+
+// GetJobLogsStream implements storage.LogStreamer, streaming a job pod's
+// logs from the Kubernetes pod log API.
+func (s *Storage) GetJobLogsStream(jobID string, opts storage.LogStreamOptions) (io.ReadCloser, error) {
+	return s.podLogsStream(s.jobPodName(jobID), opts)
+}
+
+func (s *Storage) podLogsStream(podName string, opts storage.LogStreamOptions) (io.ReadCloser, error) {
+	plo := &v1.PodLogOptions{
+		Follow:     opts.Follow,
+		Timestamps: opts.Timestamps,
+	}
+	if opts.Tail > 0 {
+		tail := int64(opts.Tail)
+		plo.TailLines = &tail
+	}
+	if opts.Since != "" {
+		if since, err := time.Parse(time.RFC3339, opts.Since); err == nil {
+			t := metav1.NewTime(since)
+			plo.SinceTime = &t
+		} else if d, err := time.ParseDuration(opts.Since); err == nil {
+			secs := int64(d.Seconds())
+			plo.SinceSeconds = &secs
+		}
+	}
+
+	req := s.client.CoreV1().Pods(s.namespace).GetLogs(podName, plo)
+	return req.Stream()
+}
@@ -0,0 +1,32 @@
+package kube
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Azure/brigade/pkg/api/metrics"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Ping implements storage.Pinger by fetching s.namespace and bounding the
+// wait with timeout, the same dial-wait shape OpenShift's
+// WaitForSuccessfulDial uses for its own readiness checks.
+func (s *Storage) Ping(timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.client.CoreV1().Namespaces().Get(s.namespace, metav1.GetOptions{})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			metrics.IncKubeError("get-namespace")
+			return fmt.Errorf("error reaching kube api: %v", err)
+		}
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out after %s waiting for kube api", timeout)
+	}
+}
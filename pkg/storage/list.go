@@ -0,0 +1,35 @@
+package storage
+
+// ListOptions mirrors the Kubernetes list call options pkg/storage/kube
+// passes straight through to client-go, plus the server-side sort brigade
+// dashboards ask for so they don't have to fetch everything to render a
+// table.
+type ListOptions struct {
+	Limit         int64
+	Continue      string
+	LabelSelector string
+	FieldSelector string
+	// SortBy is "startTime" or "status" for builds and jobs, "name" or "id"
+	// for projects; empty means unsorted (the backend's natural order).
+	SortBy string
+	// Order is "asc" or "desc"; empty means "asc".
+	Order string
+}
+
+// List is the generic paginated response envelope for list endpoints,
+// shaped like a Kubernetes list: a page of Items plus an opaque Continue
+// token and the estimated RemainingItemCount.
+type List struct {
+	Items              interface{} `json:"items"`
+	Continue           string      `json:"continue,omitempty"`
+	RemainingItemCount *int64      `json:"remainingItemCount,omitempty"`
+}
+
+// ListingStore is implemented by stores that support paginated, filtered,
+// sorted listing on top of their plain Get* methods. Stores that
+// don't implement it only ever serve the legacy, unbounded response shape.
+type ListingStore interface {
+	ListProjects(opts ListOptions) (*List, error)
+	ListProjectBuilds(projectID string, opts ListOptions) (*List, error)
+	ListBuildJobs(buildID string, opts ListOptions) (*List, error)
+}
@@ -0,0 +1,63 @@
+// Package storage defines the persistence contract the API server and
+// controller use to read and write Brigade's domain objects. Concrete
+// backends (currently just pkg/storage/kube) implement Store against
+// whatever they use to hold state.
+package storage
+
+import (
+	"io"
+	"time"
+
+	"github.com/Azure/brigade/pkg/brigade"
+)
+
+// Store is the storage backend contract used by pkg/api. A Store is
+// responsible for translating Brigade's domain objects into whatever the
+// underlying backend persists them as (Kubernetes secrets and pods, today).
+type Store interface {
+	GetProjects() ([]*brigade.Project, error)
+	GetProject(id string) (*brigade.Project, error)
+
+	GetBuild(id string) (*brigade.Build, error)
+	GetProjectBuilds(projectID string) ([]*brigade.Build, error)
+	CreateBuild(b *brigade.Build) (*brigade.Build, error)
+	CancelBuild(id string) error
+
+	GetBuildJobs(buildID string) ([]*brigade.Job, error)
+	GetJob(id string) (*brigade.Job, error)
+
+	GetBuildLogs(buildID string) ([]byte, error)
+	GetJobLogs(jobID string) ([]byte, error)
+}
+
+// LogStreamOptions controls how GetBuildLogsStream/GetJobLogsStream read
+// from the underlying log source.
+type LogStreamOptions struct {
+	// Follow keeps the stream open and delivers new lines as they are
+	// written, closing only when the source closes or the returned reader
+	// is closed.
+	Follow bool
+	// Since limits the stream to lines written at or after this point: an
+	// RFC3339 timestamp, or a duration (e.g. "10m") counted back from now.
+	// Empty means no lower bound.
+	Since string
+	// Tail limits the stream to the last N lines already written. 0 means
+	// no tailing (start from the beginning, subject to Since).
+	Tail int
+	// Timestamps prefixes each line with its RFC3339Nano timestamp.
+	Timestamps bool
+}
+
+// LogStreamer is implemented by stores that can stream logs incrementally
+// instead of returning a single buffered blob.
+type LogStreamer interface {
+	GetBuildLogsStream(buildID string, opts LogStreamOptions) (io.ReadCloser, error)
+	GetJobLogsStream(jobID string, opts LogStreamOptions) (io.ReadCloser, error)
+}
+
+// Pinger is implemented by stores that can cheaply verify connectivity to
+// their backend. It backs the /readyz probe: a Ping that doesn't return
+// within timeout is treated as not-ready.
+type Pinger interface {
+	Ping(timeout time.Duration) error
+}